@@ -0,0 +1,271 @@
+#!/bin/bash
+
+# 是否允许从 AUR 安装官方仓库中不存在的软件（仅 Arch 系发行版，需要 yay 或 paru）
+# 可在运行前通过环境变量 ALLOW_AUR=1 开启
+allow_aur="${ALLOW_AUR:-0}"
+
+# 获取脚本所在目录的路径
+script_dir=$(dirname "$0")
+
+# 定义软件列表文件路径
+software_list="${script_dir}/packages.txt"
+
+# 检查软件列表文件是否存在
+if [ ! -f "$software_list" ]; then
+    echo "软件列表文件 $software_list 不存在!"
+    exit 1
+fi
+
+# 检测可用的包管理器，APT 包名可带 ":架构" 或 "/目标发行版"
+if command -v apt-get > /dev/null; then
+    manager="apt"
+    name_pattern='^[a-z0-9][a-z0-9.+-]*(:[a-z0-9][a-z0-9-]*)?(/[a-z0-9][a-z0-9.+~-]*)?$'
+elif command -v pacman > /dev/null; then
+    manager="pacman"
+    name_pattern='^[a-z0-9@_+][a-z0-9@._+-]*$'
+else
+    manager=""
+    name_pattern=""
+    echo "未找到 apt-get 或 pacman，将只安装带前缀的条目。"
+fi
+
+# 非 root 用户通过 sudo 执行
+sudo=""
+if [ "$(id -u)" -ne 0 ]; then
+    sudo="sudo"
+fi
+
+# 检测 AUR 助手，yay 和 paru 均拒绝以 root 身份运行
+aur_helper=""
+if [ "$manager" = "pacman" ] && [ "$allow_aur" = "1" ] && [ -z "$sudo" ]; then
+    echo "已开启 AUR 支持，但 yay 和 paru 不能以 root 身份运行，将仅从官方仓库安装。"
+elif [ "$manager" = "pacman" ] && [ "$allow_aur" = "1" ]; then
+    for helper in paru yay; do
+        if command -v "$helper" > /dev/null; then
+            aur_helper="$helper"
+            break
+        fi
+    done
+    if [ -z "$aur_helper" ]; then
+        echo "已开启 AUR 支持，但未找到 yay 或 paru，将仅从官方仓库安装。"
+    fi
+fi
+
+# 更新软件源索引
+if [ "$manager" = "apt" ]; then
+    echo "Updating APT package index..."
+    $sudo apt-get update
+elif [ "$manager" = "pacman" ]; then
+    # Arch 不支持部分升级，同步数据库时需一并升级系统，由用户确认是否继续
+    echo "Synchronizing pacman package databases and upgrading system..."
+    if ! $sudo pacman -Syu; then
+        echo "系统未完成升级，为避免部分升级，停止安装。"
+        exit 1
+    fi
+fi
+
+# 检查软件是否已经安装，第一个参数为软件来源
+is_installed() {
+    if [ "$1" = "snap" ]; then
+        snap list "${2%%@*}" > /dev/null 2>&1
+    elif [ "$1" = "flatpak" ]; then
+        flatpak info "${2#*/}" > /dev/null 2>&1
+    elif [ "$1" = "nix" ]; then
+        nix --extra-experimental-features "nix-command flakes" profile list 2> /dev/null | grep -Eq "legacyPackages\.[^.]+\.${2//./\\.}( |$)"
+    elif [ "$1" = "npm" ]; then
+        npm ls -g --depth=0 "$2" > /dev/null 2>&1
+    elif [ "$1" = "gem" ]; then
+        gem list -i "^${2}\$" > /dev/null
+    elif [ "$1" = "code" ]; then
+        code --list-extensions | grep -Fqix "$2"
+    elif [ "$1" = "docker" ]; then
+        docker image inspect "$2" > /dev/null 2>&1
+    elif [ "$1" = "apt" ]; then
+        dpkg-query -W -f='${Status}' "${2%%/*}" 2> /dev/null | grep -q "install ok installed"
+    else
+        pacman -T "$2" > /dev/null
+    fi
+}
+
+# 通过 snapd 安装软件，"名称@频道" 形式可指定频道
+# 需要经典模式的软件须在列表中显式加上 --classic，脚本不会自动放开沙箱限制
+install_snap() {
+    local name="${1%%@*}"
+    local channel="stable"
+    if [ "$name" != "$1" ]; then
+        channel="${1#*@}"
+    fi
+
+    $sudo snap install "$name" --channel="$channel" "${install_args[@]}" < /dev/null
+}
+
+# 通过 flatpak 安装软件，"远程仓库/应用ID" 形式可指定远程仓库，默认使用 flathub
+install_flatpak() {
+    local app="${1#*/}"
+    local remote="flathub"
+    if [ "$app" != "$1" ]; then
+        remote="${1%%/*}"
+    fi
+
+    if [ "$remote" = "flathub" ]; then
+        flatpak remote-add --if-not-exists flathub https://dl.flathub.org/repo/flathub.flatpakrepo
+    elif ! flatpak remotes --columns=name | grep -qx "$remote"; then
+        echo "未找到 flatpak 远程仓库 $remote，请先通过 flatpak remote-add 添加。"
+        return 1
+    fi
+
+    flatpak install -y --noninteractive "$remote" "$app" "${install_args[@]}" < /dev/null
+}
+
+# 安装全局 npm 包，全局目录不可写时（如发行版自带的 Node.js）通过 sudo 安装
+install_npm() {
+    if [ -w "$(npm prefix -g)" ]; then
+        npm install -g "$1" "${install_args[@]}" < /dev/null
+    else
+        $sudo npm install -g "$1" "${install_args[@]}" < /dev/null
+    fi
+}
+
+# 安装 Ruby gem，gem 目录不可写时（如发行版自带的 Ruby）通过 sudo 安装
+install_gem() {
+    if [ -w "$(gem environment gemdir)" ]; then
+        gem install "$1" "${install_args[@]}" < /dev/null
+    else
+        $sudo gem install "$1" "${install_args[@]}" < /dev/null
+    fi
+}
+
+# 安装软件，第一个参数为软件来源
+# pacman 会把 python3 等虚拟包名解析为提供它的软件包，官方仓库中找不到时才回退到 AUR 助手
+install_package() {
+    local package="$2"
+    if [ "$1" = "snap" ]; then
+        install_snap "$package"
+    elif [ "$1" = "flatpak" ]; then
+        install_flatpak "$package"
+    elif [ "$1" = "nix" ]; then
+        nix --extra-experimental-features "nix-command flakes" profile install "nixpkgs#$package" "${install_args[@]}" < /dev/null
+    elif [ "$1" = "npm" ]; then
+        install_npm "$package"
+    elif [ "$1" = "gem" ]; then
+        install_gem "$package"
+    elif [ "$1" = "code" ]; then
+        code --install-extension "$package" "${install_args[@]}" < /dev/null
+    elif [ "$1" = "docker" ]; then
+        docker pull "$package" "${install_args[@]}" < /dev/null
+    elif [ "$1" = "apt" ]; then
+        $sudo apt-get install -y "$package" "${install_args[@]}" < /dev/null
+    elif pacman -Sp --print-format %n "$package" > /dev/null 2>&1; then
+        $sudo pacman -S --needed --noconfirm "$package" "${install_args[@]}" < /dev/null
+    elif [ -n "$aur_helper" ]; then
+        echo "$package 不在官方仓库中，尝试通过 $aur_helper 从 AUR 安装..."
+        "$aur_helper" -S --needed --noconfirm "$package" "${install_args[@]}" < /dev/null
+    elif [ "$allow_aur" = "1" ]; then
+        echo "$package 不在官方仓库中，且当前无法使用 AUR 助手，跳过。"
+        return 1
+    else
+        echo "$package 不在官方仓库中，如需从 AUR 安装请设置 ALLOW_AUR=1 并安装 yay 或 paru。"
+        return 1
+    fi
+}
+
+# 逐行读取软件列表文件并安装软件，记录已处理的软件以跳过重复条目
+seen=$'\n'
+while IFS= read -r line || [ -n "$line" ]; do
+    # 去除行内注释及首尾空白，跳过空行和注释行
+    package="${line%%#*}"
+    package="${package#"${package%%[![:space:]]*}"}"
+    package="${package%"${package##*[![:space:]]}"}"
+    if [ -z "$package" ]; then
+        continue
+    fi
+
+    # 包名之后以空格分隔的内容作为额外参数传给包管理器
+    read -r package extra <<< "$package"
+    read -ra install_args <<< "$extra"
+
+    # 根据前缀选择安装来源，未带前缀的条目通过系统包管理器安装
+    # "snap:" 前缀表示通过 snapd 安装，经典模式需在包名后显式加上 --classic
+    # "flatpak:" 前缀表示通过 flatpak 安装，应用 ID 区分大小写
+    # "nix:" 前缀表示通过 nix profile 安装，名称区分大小写
+    # "npm:" 前缀表示安装全局 npm 包
+    # "gem:" 前缀表示安装 Ruby gem，名称区分大小写
+    # "vscode:" 前缀表示安装 VS Code 扩展
+    # "docker:" 前缀表示拉取 Docker 镜像，镜像标签区分大小写
+    # 其余包名统一转为小写
+    backend="$manager"
+    pattern="$name_pattern"
+    case "$package" in
+        snap:*)
+            backend="snap"
+            package=$(printf '%s' "${package#snap:}" | tr '[:upper:]' '[:lower:]')
+            pattern='^[a-z0-9][a-z0-9-]*(@[a-z0-9][a-z0-9./-]*)?$'
+            ;;
+        flatpak:*)
+            backend="flatpak"
+            package="${package#flatpak:}"
+            pattern='^([A-Za-z0-9_-]+/)?[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)+$'
+            ;;
+        nix:*)
+            backend="nix"
+            package="${package#nix:}"
+            pattern='^[A-Za-z0-9_][A-Za-z0-9._+-]*$'
+            ;;
+        npm:*)
+            backend="npm"
+            package=$(printf '%s' "${package#npm:}" | tr '[:upper:]' '[:lower:]')
+            pattern='^(@[a-z0-9][a-z0-9._-]*/)?[a-z0-9][a-z0-9._-]*$'
+            ;;
+        gem:*)
+            backend="gem"
+            package="${package#gem:}"
+            pattern='^[A-Za-z0-9_][A-Za-z0-9._-]*$'
+            ;;
+        vscode:*)
+            backend="code"
+            package="${package#vscode:}"
+            pattern='^[A-Za-z0-9][A-Za-z0-9-]*\.[A-Za-z0-9][A-Za-z0-9._-]*$'
+            ;;
+        docker:*)
+            backend="docker"
+            package="${package#docker:}"
+            pattern='^[a-z0-9][a-z0-9._/-]*(:[A-Za-z0-9_][A-Za-z0-9._-]*)?$'
+            ;;
+        *)
+            package=$(printf '%s' "$package" | tr '[:upper:]' '[:lower:]')
+            ;;
+    esac
+
+    if [ -z "$backend" ]; then
+        echo "未找到 apt-get 或 pacman，跳过 $package。"
+        continue
+    fi
+
+    # 校验包名的合法性
+    if [[ ! "$package" =~ $pattern ]]; then
+        echo "无效的软件名 \"$package\"，跳过。"
+        continue
+    fi
+
+    # 跳过列表中重复出现的软件，不区分大小写
+    key="$backend:$(printf '%s' "$package" | tr '[:upper:]' '[:lower:]')"
+    case "$seen" in
+        *$'\n'"$key"$'\n'*)
+            echo "$package 在列表中重复出现，跳过。"
+            continue
+            ;;
+    esac
+    seen="$seen$key"$'\n'
+
+    echo "Checking if $package is installed..."
+    if [ "$backend" != "$manager" ] && ! command -v "$backend" > /dev/null; then
+        echo "未找到 $backend，跳过 $package。"
+    elif is_installed "$backend" "$package"; then
+        echo "$package 已经安装，跳过。"
+    else
+        echo "Installing $package..."
+        install_package "$backend" "$package"
+    fi
+done < "$software_list"
+
+echo "所有软件安装完成！"
@@ -1,4 +1,5 @@
 @echo off
+setlocal
 
 REM 检查是否存在软件列表文件
 if not exist "software_list.txt" (
@@ -6,11 +7,125 @@ if not exist "software_list.txt" (
     exit /b
 )
 
-REM 逐行读取软件列表文件并安装软件
-for /f "tokens=*" %%a in (software_list.txt) do (
-    echo Installing software: %%a
-    winget install %%a 
+REM 逐行读取软件列表文件并安装软件，跳过空行、注释行以及行内注释
+for /f "usebackq eol=# tokens=1 delims=#" %%l in ("software_list.txt") do (
+    for /f "tokens=1,* delims= 	" %%a in ("%%l") do (
+        set "args=%%b"
+        call :install "%%a"
+    )
 )
 
 echo All software is already installed!
 pause
+goto :eof
+
+REM 校验软件 ID 及额外参数后调用对应的包管理器安装
+REM "choco:" 前缀表示通过 Chocolatey 安装
+REM "scoop:" 前缀表示通过 Scoop 安装
+REM "npm:" 前缀表示安装全局 npm 包
+REM "vscode:" 前缀表示安装 VS Code 扩展
+REM "docker:" 前缀表示拉取 Docker 镜像
+:install
+set "id=%~1"
+set "manager=winget"
+if /i "%id:~0,6%"=="choco:" (
+    set "manager=choco"
+    set "id=%id:~6%"
+)
+if /i "%id:~0,6%"=="scoop:" (
+    set "manager=scoop"
+    set "id=%id:~6%"
+)
+if /i "%id:~0,4%"=="npm:" (
+    set "manager=npm"
+    set "id=%id:~4%"
+)
+if /i "%id:~0,7%"=="vscode:" (
+    set "manager=code"
+    set "id=%id:~7%"
+)
+if /i "%id:~0,7%"=="docker:" (
+    set "manager=docker"
+    set "id=%id:~7%"
+)
+REM ID 只允许包管理器支持的字符，避免特殊符号被当作命令执行
+REM scoop 的 ID 可带 bucket 前缀，npm 包名可带 @scope 前缀且只能使用小写字母，VS Code 扩展 ID 形如 publisher.name，Docker 镜像名可带仓库路径和 :tag
+REM findstr 的 [a-z] 区间会匹配大写字母，只允许小写时需逐个列出字母
+set "pattern=[0-9a-zA-Z][0-9a-zA-Z._+-]*"
+set "qualified=%pattern%"
+if "%manager%"=="scoop" (
+    set "pattern=[0-9a-zA-Z][0-9a-zA-Z._-]*"
+    set "qualified=[0-9a-zA-Z][0-9a-zA-Z._-]*/[0-9a-zA-Z][0-9a-zA-Z._-]*"
+)
+if "%manager%"=="npm" (
+    set "pattern=[0-9abcdefghijklmnopqrstuvwxyz][0-9abcdefghijklmnopqrstuvwxyz._-]*"
+    set "qualified=@[0-9abcdefghijklmnopqrstuvwxyz][0-9abcdefghijklmnopqrstuvwxyz._-]*/[0-9abcdefghijklmnopqrstuvwxyz][0-9abcdefghijklmnopqrstuvwxyz._-]*"
+)
+if "%manager%"=="code" set "pattern=[0-9a-zA-Z][0-9a-zA-Z_-]*\.[0-9a-zA-Z][0-9a-zA-Z._-]*"
+if "%manager%"=="code" set "qualified=%pattern%"
+if "%manager%"=="docker" (
+    set "pattern=[0-9abcdefghijklmnopqrstuvwxyz][0-9abcdefghijklmnopqrstuvwxyz._/-]*"
+    set "qualified=[0-9abcdefghijklmnopqrstuvwxyz][0-9abcdefghijklmnopqrstuvwxyz._/-]*:[0-9a-zA-Z_][0-9a-zA-Z._-]*"
+)
+(set id) | findstr /r /x /c:"id=%pattern%" /c:"id=%qualified%" >nul || (
+    echo Invalid software ID "%id%": unsupported characters, skipped.
+    goto :eof
+)
+REM ID 之后的内容作为额外参数传给包管理器，其中不允许出现命令分隔符和重定向符号
+if defined args (
+    (set args) | findstr /r /c:"^args=.*[&|<>]" >nul && (
+        echo Invalid arguments for "%id%": unsupported characters, skipped.
+        goto :eof
+    )
+)
+if not "%manager%"=="winget" (
+    where %manager% >nul 2>&1 || (
+        echo %manager% not found, skipped %id%.
+        goto :eof
+    )
+)
+REM 跳过列表中重复出现的软件 ID（环境变量名不区分大小写）
+if defined seen.%manager%.%id% (
+    echo Duplicate software ID "%id%", skipped.
+    goto :eof
+)
+set "seen.%manager%.%id%=1"
+echo Installing software: %id% %args%
+goto :install_%manager%
+
+:install_winget
+winget install %id% %args%
+goto :eof
+
+REM Chocolatey 需要在管理员终端中运行
+:install_choco
+choco install %id% -y %args%
+goto :eof
+
+REM scoop 是批处理包装脚本，需要通过 call 调用
+:install_scoop
+call scoop install %id% %args%
+goto :eof
+
+REM npm 是批处理包装脚本，需要通过 call 调用
+:install_npm
+call npm install -g %id% %args%
+goto :eof
+
+REM code 是批处理包装脚本，需要通过 call 调用，已安装的扩展直接跳过
+:install_code
+call code --list-extensions | findstr /i /x /c:"%id%" >nul && (
+    echo %id% is already installed, skipped.
+    goto :eof
+)
+call code --install-extension %id% %args%
+goto :eof
+
+REM 本地已存在的镜像直接跳过
+:install_docker
+docker image inspect %id% >nul 2>&1 && (
+    echo %id% is already present, skipped.
+    goto :eof
+)
+docker pull %id% %args%
+goto :eof
@@ -20,14 +20,134 @@ if [ ! -f "$software_list" ]; then
     exit 1
 fi
 
-# 逐行读取软件列表文件并安装软件
-while IFS= read -r package; do
+# 检查软件是否已经安装，第一个参数为软件来源
+is_installed() {
+    if [ "$1" = "brew" ]; then
+        brew list "${brew_args[@]}" --versions "$2" > /dev/null
+    elif [ "$1" = "nix" ]; then
+        nix --extra-experimental-features "nix-command flakes" profile list 2> /dev/null | grep -Eq "legacyPackages\.[^.]+\.${2//./\\.}( |$)"
+    elif [ "$1" = "npm" ]; then
+        npm ls -g --depth=0 "$2" > /dev/null 2>&1
+    elif [ "$1" = "gem" ]; then
+        gem list -i "^${2}\$" > /dev/null
+    elif [ "$1" = "code" ]; then
+        code --list-extensions | grep -Fqix "$2"
+    elif [ "$1" = "docker" ]; then
+        docker image inspect "$2" > /dev/null 2>&1
+    fi
+}
+
+# 安装 Ruby gem，gem 目录不可写时（如系统自带的 Ruby）通过 sudo 安装
+install_gem() {
+    if [ -w "$(gem environment gemdir)" ]; then
+        gem install "$1" "${install_args[@]}" < /dev/null
+    else
+        sudo gem install "$1" "${install_args[@]}" < /dev/null
+    fi
+}
+
+# 安装软件，第一个参数为软件来源
+install_package() {
+    if [ "$1" = "brew" ]; then
+        brew install "${brew_args[@]}" "$2" "${install_args[@]}" < /dev/null
+    elif [ "$1" = "nix" ]; then
+        nix --extra-experimental-features "nix-command flakes" profile install "nixpkgs#$2" "${install_args[@]}" < /dev/null
+    elif [ "$1" = "npm" ]; then
+        npm install -g "$2" "${install_args[@]}" < /dev/null
+    elif [ "$1" = "gem" ]; then
+        install_gem "$2"
+    elif [ "$1" = "code" ]; then
+        code --install-extension "$2" "${install_args[@]}" < /dev/null
+    elif [ "$1" = "docker" ]; then
+        docker pull "$2" "${install_args[@]}" < /dev/null
+    fi
+}
+
+# 逐行读取软件列表文件并安装软件，记录已处理的软件以跳过重复条目
+seen=$'\n'
+while IFS= read -r line || [ -n "$line" ]; do
+    # 去除行内注释及首尾空白，跳过空行和注释行
+    package="${line%%#*}"
+    package="${package#"${package%%[![:space:]]*}"}"
+    package="${package%"${package##*[![:space:]]}"}"
+    if [ -z "$package" ]; then
+        continue
+    fi
+
+    # 软件名之后以空格分隔的内容作为额外参数传给包管理器
+    read -r package extra <<< "$package"
+    read -ra install_args <<< "$extra"
+
+    # 根据前缀选择安装来源，未带前缀的条目通过 Homebrew 安装
+    # "cask:" 前缀表示以 cask 方式安装
+    # "nix:" 前缀表示通过 nix profile 安装，名称区分大小写
+    # "npm:" 前缀表示安装全局 npm 包
+    # "gem:" 前缀表示安装 Ruby gem，名称区分大小写
+    # "vscode:" 前缀表示安装 VS Code 扩展
+    # "docker:" 前缀表示拉取 Docker 镜像，镜像标签区分大小写
+    # 其余软件名统一转为小写，可用 "用户/仓库/软件名" 指定第三方 tap 中的软件
+    manager="brew"
+    brew_args=()
+    pattern='^([a-z0-9_-]+/[a-z0-9_-]+/)?[a-z0-9][a-z0-9@._+-]*$'
+    case "$package" in
+        cask:*)
+            brew_args=(--cask)
+            package=$(printf '%s' "${package#cask:}" | tr '[:upper:]' '[:lower:]')
+            ;;
+        nix:*)
+            manager="nix"
+            package="${package#nix:}"
+            pattern='^[A-Za-z0-9_][A-Za-z0-9._+-]*$'
+            ;;
+        npm:*)
+            manager="npm"
+            package=$(printf '%s' "${package#npm:}" | tr '[:upper:]' '[:lower:]')
+            pattern='^(@[a-z0-9][a-z0-9._-]*/)?[a-z0-9][a-z0-9._-]*$'
+            ;;
+        gem:*)
+            manager="gem"
+            package="${package#gem:}"
+            pattern='^[A-Za-z0-9_][A-Za-z0-9._-]*$'
+            ;;
+        vscode:*)
+            manager="code"
+            package="${package#vscode:}"
+            pattern='^[A-Za-z0-9][A-Za-z0-9-]*\.[A-Za-z0-9][A-Za-z0-9._-]*$'
+            ;;
+        docker:*)
+            manager="docker"
+            package="${package#docker:}"
+            pattern='^[a-z0-9][a-z0-9._/-]*(:[A-Za-z0-9_][A-Za-z0-9._-]*)?$'
+            ;;
+        *)
+            package=$(printf '%s' "$package" | tr '[:upper:]' '[:lower:]')
+            ;;
+    esac
+
+    # 校验软件名的合法性
+    if [[ ! "$package" =~ $pattern ]]; then
+        echo "无效的软件名 \"$package\"，跳过。"
+        continue
+    fi
+
+    # 跳过列表中重复出现的软件，cask 与同名 formula 视为不同软件
+    key="$manager:${brew_args[*]}:$package"
+    case "$seen" in
+        *$'\n'"$key"$'\n'*)
+            echo "$package 在列表中重复出现，跳过。"
+            continue
+            ;;
+    esac
+    seen="$seen$key"$'\n'
+
     echo "Checking if $package is installed..."
-    if brew list --versions "$package" > /dev/null; then
+    if [ "$manager" != "brew" ] && ! command -v "$manager" > /dev/null; then
+        echo "未找到 $manager，跳过 $package。"
+    elif is_installed "$manager" "$package"; then
         echo "$package 已经安装，跳过。"
     else
         echo "Installing $package..."
-        brew install "$package"
+        install_package "$manager" "$package"
     fi
 done < "$software_list"
 